@@ -0,0 +1,216 @@
+package timezone
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// zone1970TabName is the IANA tzdata file this package reads for geographic
+// metadata, following the pattern of the Haskell timezone-unix package's
+// ZoneDescription/getZoneDescriptions.
+const zone1970TabName = "zone1970.tab"
+
+// ZoneDescription is a single entry of zone1970.tab: an Olson zone name
+// together with the countries and coordinates it's associated with.
+type ZoneDescription struct {
+	Countries []string
+	Latitude  float64
+	Longitude float64
+	Name      string
+	Comment   string
+}
+
+// coordinateRegex matches the ISO 6709 coordinate pairs used by
+// zone1970.tab: +DDMM+DDDMM or +DDMMSS+DDDMMSS.
+var coordinateRegex = regexp.MustCompile(`^([+-])(\d{2})(\d{2})(\d{2})?([+-])(\d{3})(\d{2})(\d{2})?$`)
+
+// CountryCodes returns every ISO 3166-1 alpha-2 country code listed in
+// zone1970.tab.
+func CountryCodes() ([]string, error) {
+	zones, err := loadZone1970()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+
+	var codes []string
+
+	for _, zone := range zones {
+		for _, cc := range zone.Countries {
+			if seen[cc] {
+				continue
+			}
+
+			seen[cc] = true
+
+			codes = append(codes, cc)
+		}
+	}
+
+	return codes, nil
+}
+
+// ZonesForCountry returns every zone associated with the given ISO 3166-1
+// alpha-2 country code (case-insensitive).
+func ZonesForCountry(cc string) ([]ZoneDescription, error) {
+	zones, err := loadZone1970()
+	if err != nil {
+		return nil, err
+	}
+
+	cc = strings.ToUpper(cc)
+
+	var matched []ZoneDescription
+
+	for _, zone := range zones {
+		for _, zcc := range zone.Countries {
+			if zcc == cc {
+				matched = append(matched, zone)
+				break
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// CountryForZone returns the primary ISO 3166-1 alpha-2 country code for the
+// given Olson zone name, e.g. "America/Sao_Paulo" -> "BR".
+func CountryForZone(name string) (string, error) {
+	zones, err := loadZone1970()
+	if err != nil {
+		return "", err
+	}
+
+	for _, zone := range zones {
+		if zone.Name != name {
+			continue
+		}
+
+		if len(zone.Countries) == 0 {
+			return "", fmt.Errorf("zone %q has no associated country", name)
+		}
+
+		return zone.Countries[0], nil
+	}
+
+	return "", fmt.Errorf("zone %q not found in %s", name, zone1970TabName)
+}
+
+// loadZone1970 reads zone1970.tab from the configured zoneinfo directory,
+// falling back to the copy embedded in this module.
+func loadZone1970() ([]ZoneDescription, error) {
+	if dir := zoneinfoDir(defaultZoneinfoDir); dir != "" {
+		if data, err := os.ReadFile(filepath.Join(dir, zone1970TabName)); err == nil {
+			return parseZone1970(data)
+		}
+	}
+
+	data, err := loadEmbeddedTZData(zone1970TabName)
+	if err != nil {
+		return nil, fmt.Errorf("%s not available: %w", zone1970TabName, err)
+	}
+
+	return parseZone1970(data)
+}
+
+// parseZone1970 parses the tab-separated codes/coordinates/TZ/comments
+// columns of zone1970.tab.
+func parseZone1970(data []byte) ([]ZoneDescription, error) {
+	var zones []ZoneDescription
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cols := strings.SplitN(line, "\t", 4)
+		if len(cols) < 3 {
+			continue
+		}
+
+		lat, lon, err := parseCoordinate(cols[1])
+		if err != nil {
+			continue
+		}
+
+		zone := ZoneDescription{
+			Countries: strings.Split(cols[0], ","),
+			Latitude:  lat,
+			Longitude: lon,
+			Name:      cols[2],
+		}
+
+		if len(cols) == 4 {
+			zone.Comment = cols[3]
+		}
+
+		zones = append(zones, zone)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// parseCoordinate parses an ISO 6709 coordinate pair as used by
+// zone1970.tab, e.g. "+2128-05753" or "+242246+1053633".
+func parseCoordinate(s string) (lat, lon float64, err error) {
+	m := coordinateRegex.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, fmt.Errorf("invalid ISO 6709 coordinate %q", s)
+	}
+
+	lat, err = dmsToDecimal(m[1], m[2], m[3], m[4])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lon, err = dmsToDecimal(m[5], m[6], m[7], m[8])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lat, lon, nil
+}
+
+// dmsToDecimal converts a signed degrees/minutes/seconds triple (seconds
+// optional) into a signed decimal degree value.
+func dmsToDecimal(sign, degrees, minutes, seconds string) (float64, error) {
+	d, err := strconv.Atoi(degrees)
+	if err != nil {
+		return 0, err
+	}
+
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, err
+	}
+
+	var s int
+
+	if seconds != "" {
+		s, err = strconv.Atoi(seconds)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	value := float64(d) + float64(m)/60 + float64(s)/3600
+	if sign == "-" {
+		value = -value
+	}
+
+	return value, nil
+}
@@ -0,0 +1,98 @@
+package timezone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	tests := map[string]struct {
+		Value       string
+		Lat, Lon    float64
+		ExpectError bool
+	}{
+		"DDMM+DDDMM": {
+			Value: "+2728-05349",
+			Lat:   27 + 28.0/60,
+			Lon:   -(53 + 49.0/60),
+		},
+		"DDMMSS+DDDMMSS": {
+			Value: "+404251+0034235",
+			Lat:   40 + 42.0/60 + 51.0/3600,
+			Lon:   3 + 42.0/60 + 35.0/3600,
+		},
+		"invalid": {
+			Value:       "not-a-coordinate",
+			ExpectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lat, lon, err := parseCoordinate(test.Value)
+
+			if test.ExpectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, test.Lat, lat, 0.0001)
+			assert.InDelta(t, test.Lon, lon, 0.0001)
+		})
+	}
+}
+
+func TestParseZone1970(t *testing.T) {
+	data := []byte("# comment\n" +
+		"BR\t-2328-04625\tAmerica/Sao_Paulo\n" +
+		"CH,DE,LI\t+4723+00832\tEurope/Zurich\tSwiss time\n" +
+		"\n")
+
+	zones, err := parseZone1970(data)
+	require.NoError(t, err)
+	require.Len(t, zones, 2)
+
+	assert.Equal(t, []string{"BR"}, zones[0].Countries)
+	assert.Equal(t, "America/Sao_Paulo", zones[0].Name)
+	assert.Empty(t, zones[0].Comment)
+
+	assert.Equal(t, []string{"CH", "DE", "LI"}, zones[1].Countries)
+	assert.Equal(t, "Europe/Zurich", zones[1].Name)
+	assert.Equal(t, "Swiss time", zones[1].Comment)
+}
+
+func TestCountryCodesAndZonesForCountry(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	SetZoneinfoDir(t.TempDir())
+
+	codes, err := CountryCodes()
+	require.NoError(t, err)
+	assert.Contains(t, codes, "BR")
+
+	zones, err := ZonesForCountry("br")
+	require.NoError(t, err)
+
+	var names []string
+	for _, z := range zones {
+		names = append(names, z.Name)
+	}
+
+	assert.Contains(t, names, "America/Sao_Paulo")
+}
+
+func TestCountryForZone(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	SetZoneinfoDir(t.TempDir())
+
+	cc, err := CountryForZone("America/Sao_Paulo")
+	require.NoError(t, err)
+	assert.Equal(t, "BR", cc)
+
+	_, err = CountryForZone("Not/A_Real_Zone")
+	assert.Error(t, err)
+}
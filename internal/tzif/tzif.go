@@ -0,0 +1,156 @@
+// Package tzif implements a minimal parser for the TZif (zoneinfo) binary
+// format used by /etc/localtime and the files under /usr/share/zoneinfo.
+//
+// See https://datatracker.ietf.org/doc/html/rfc8536 for the on-disk format.
+package tzif
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const headerLen = 44
+
+// Header is the parsed fixed-size header of a TZif block (RFC 8536 section 3.1).
+type Header struct {
+	Version   byte
+	UTCount   uint32 // tzh_ttisutcnt
+	StdCount  uint32 // tzh_ttisstdcnt
+	LeapCount uint32 // tzh_leapcnt
+	TimeCount uint32 // tzh_timecnt
+	TypeCount uint32 // tzh_typecnt
+	CharCount uint32 // tzh_charcnt
+}
+
+// Type is a single "ttinfo" local time type record.
+type Type struct {
+	UTOffset int32 // seconds east of UTC
+	IsDST    bool
+	Abbrev   string
+}
+
+// File is a parsed TZif (zoneinfo) file.
+type File struct {
+	Header      Header
+	Transitions []int64 // transition times, seconds since the Unix epoch
+	Types       []Type  // the local time type in effect after each transition
+}
+
+// Parse parses the TZif payload in data. v1 files carry a single 32-bit
+// block; v2 and v3 files repeat the data as a second, 64-bit block, which
+// Parse prefers since it covers the full transition range.
+func Parse(data []byte) (*File, error) {
+	file, size, err := parseBlock(data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Header.Version == 0 {
+		return file, nil
+	}
+
+	// v2/v3: a second, 64-bit block immediately follows the first.
+	v2, _, err := parseBlock(data[size:], true)
+	if err != nil {
+		return file, nil
+	}
+
+	return v2, nil
+}
+
+// parseBlock parses a single TZif block (the v1 32-bit block, or a v2/v3
+// 64-bit block) and returns it along with the number of bytes it occupies.
+func parseBlock(data []byte, is64bit bool) (*File, int, error) {
+	if len(data) < headerLen || string(data[:4]) != "TZif" {
+		return nil, 0, errors.New("tzif: bad magic")
+	}
+
+	hdr := Header{
+		Version:   data[4],
+		UTCount:   binary.BigEndian.Uint32(data[20:24]),
+		StdCount:  binary.BigEndian.Uint32(data[24:28]),
+		LeapCount: binary.BigEndian.Uint32(data[28:32]),
+		TimeCount: binary.BigEndian.Uint32(data[32:36]),
+		TypeCount: binary.BigEndian.Uint32(data[36:40]),
+		CharCount: binary.BigEndian.Uint32(data[40:44]),
+	}
+
+	timeSize := 4
+	if is64bit {
+		timeSize = 8
+	}
+
+	transitionsEnd := headerLen + int(hdr.TimeCount)*timeSize
+	indexEnd := transitionsEnd + int(hdr.TimeCount)
+	typesEnd := indexEnd + int(hdr.TypeCount)*6
+	abbrevEnd := typesEnd + int(hdr.CharCount)
+	leapEnd := abbrevEnd + int(hdr.LeapCount)*(timeSize+4)
+	stdEnd := leapEnd + int(hdr.StdCount)
+	utEnd := stdEnd + int(hdr.UTCount)
+
+	if len(data) < utEnd {
+		return nil, 0, fmt.Errorf("tzif: truncated file, need %d bytes, have %d", utEnd, len(data))
+	}
+
+	transitions := make([]int64, hdr.TimeCount)
+	for i := range transitions {
+		start := headerLen + i*timeSize
+		if is64bit {
+			transitions[i] = int64(binary.BigEndian.Uint64(data[start : start+8]))
+		} else {
+			transitions[i] = int64(int32(binary.BigEndian.Uint32(data[start : start+4])))
+		}
+	}
+
+	types := make([]Type, hdr.TypeCount)
+
+	abbrev := string(data[typesEnd:abbrevEnd])
+
+	for i := range types {
+		start := indexEnd + i*6
+		abbrevIdx := int(data[start+5])
+
+		if abbrevIdx >= len(abbrev) {
+			return nil, 0, fmt.Errorf("tzif: desigidx %d out of range for charcnt %d", abbrevIdx, hdr.CharCount)
+		}
+
+		end := len(abbrev)
+		if idx := indexByte(abbrev, abbrevIdx); idx >= 0 {
+			end = idx
+		}
+
+		types[i] = Type{
+			UTOffset: int32(binary.BigEndian.Uint32(data[start : start+4])),
+			IsDST:    data[start+4] != 0,
+			Abbrev:   abbrev[abbrevIdx:end],
+		}
+	}
+
+	file := &File{
+		Header:      hdr,
+		Transitions: transitions,
+		Types:       make([]Type, 0, len(transitions)),
+	}
+
+	for i := range transitions {
+		idx := int(data[transitionsEnd+i])
+		if idx < len(types) {
+			file.Types = append(file.Types, types[idx])
+		}
+	}
+
+	return file, utEnd, nil
+}
+
+// indexByte returns the index of the first NUL byte in s at or after from,
+// or -1 if there is none.
+func indexByte(s string, from int) int {
+	for i := from; i < len(s); i++ {
+		if s[i] == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
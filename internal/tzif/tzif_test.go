@@ -0,0 +1,72 @@
+package tzif
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// utcV1 is a minimal, hand-built v1 TZif payload describing UTC: no
+// transitions and a single zero-offset, non-DST type named "UTC".
+func utcV1(t *testing.T) []byte {
+	t.Helper()
+
+	data := make([]byte, 44)
+	copy(data, "TZif")
+	data[4] = 0 // version 1
+	// tzh_typecnt = 1
+	data[39] = 1
+	// tzh_charcnt = 4 ("UTC\x00")
+	data[43] = 4
+
+	// one ttinfo record: utoffset=0, isdst=0, abbrind=0
+	data = append(data, 0, 0, 0, 0, 0, 0)
+	// abbreviation string table
+	data = append(data, []byte("UTC\x00")...)
+
+	return data
+}
+
+func TestParse(t *testing.T) {
+	file, err := Parse(utcV1(t))
+	require.NoError(t, err)
+
+	assert.Empty(t, file.Transitions)
+	assert.Equal(t, byte(0), file.Header.Version)
+	assert.Equal(t, uint32(1), file.Header.TypeCount)
+	assert.Equal(t, uint32(4), file.Header.CharCount)
+}
+
+func TestParse_BadMagic(t *testing.T) {
+	_, err := Parse([]byte("not a tzif file at all, way too short"))
+
+	assert.Error(t, err)
+}
+
+func TestParse_Truncated(t *testing.T) {
+	data := utcV1(t)
+
+	_, err := Parse(data[:len(data)-2])
+
+	assert.Error(t, err)
+}
+
+func TestParse_DesigidxOutOfRange(t *testing.T) {
+	data := make([]byte, 44)
+	copy(data, "TZif")
+	data[4] = 0 // version 1
+	// tzh_typecnt = 1
+	data[39] = 1
+	// tzh_charcnt = 1 ("\x00")
+	data[43] = 1
+
+	// one ttinfo record: utoffset=0, isdst=0, desigidx=5 (out of range for
+	// a 1 byte abbreviation table)
+	data = append(data, 0, 0, 0, 0, 0, 5)
+	data = append(data, 0)
+
+	_, err := Parse(data)
+
+	assert.Error(t, err)
+}
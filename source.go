@@ -0,0 +1,26 @@
+package timezone
+
+// TZSource identifies where a resolved timezone name came from, so callers can
+// tell a deliberate configuration (env, symlink, config file, registry) from a
+// guess.
+type TZSource string
+
+const (
+	// SourceEnv means the timezone was resolved from the TZ environment variable.
+	SourceEnv TZSource = "env"
+	// SourceSymlink means the timezone was resolved from the /etc/localtime symlink.
+	SourceSymlink TZSource = "symlink"
+	// SourceConfigFile means the timezone was resolved from a distribution
+	// specific configuration file (e.g. /etc/timezone, /etc/sysconfig/clock).
+	SourceConfigFile TZSource = "config"
+	// SourceRegistry means the timezone was resolved from the Windows registry.
+	SourceRegistry TZSource = "registry"
+	// SourceFingerprint means the timezone was resolved by matching the
+	// contents of /etc/localtime against the zoneinfo directory by hash,
+	// used when /etc/localtime is a regular file rather than a symlink.
+	SourceFingerprint TZSource = "fingerprint"
+	// SourceHardlink means the timezone was resolved by matching
+	// /etc/localtime against the zoneinfo directory by device and inode,
+	// used on NetBSD and OpenBSD where /etc/localtime is a hardlink.
+	SourceHardlink TZSource = "hardlink"
+)
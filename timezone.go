@@ -1,4 +1,4 @@
-//go:build !darwin && !linux && !windows
+//go:build !windows && (!unix || android || ios)
 
 package timezone
 
@@ -7,7 +7,17 @@ import (
 	"runtime"
 )
 
+// defaultZoneinfoDir is empty here: there's no known native zoneinfo tree
+// for this platform, so CountryCodes/ZonesForCountry/CountryForZone rely on
+// the embedded tzdata unless SetZoneinfoDir or ZONEINFO point somewhere else.
+const defaultZoneinfoDir = ""
+
 // Name always return an error as it's not implemented yet for current os.
 func Name() (string, error) {
 	return "", fmt.Errorf("name not implemented for '%s'", runtime.GOOS)
 }
+
+// NameWithSource always return an error as it's not implemented yet for current os.
+func NameWithSource() (string, TZSource, error) {
+	return "", "", fmt.Errorf("name not implemented for '%s'", runtime.GOOS)
+}
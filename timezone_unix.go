@@ -1,4 +1,4 @@
-//go:build darwin || linux
+//go:build unix && !android && !ios
 
 package timezone
 
@@ -6,22 +6,42 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
 
 	"github.com/yookoala/realpath"
 )
 
+// defaultZoneinfoDir is where this platform keeps the IANA tzdata tree.
+const defaultZoneinfoDir = "/usr/share/zoneinfo"
+
 var timezoneRegex = regexp.MustCompile(`^\s*(TIMEZONE|ZONE)\s*=\s*\"(?P<tz>.*)\"$`)
 
+// envTZRegex matches an unquoted "TZ=value" line, as used by Solaris's
+// /etc/default/init and AIX's /etc/environment.
+var envTZRegex = regexp.MustCompile(`(?m)^\s*TZ=(?P<tz>\S+)\s*$`)
+
+// posixRuleRegex matches a POSIX TZ rule (e.g. "EST5EDT,M3.2.0,M11.1.0" or
+// "<-04>4") rather than an Olson zone name or file path.
+var posixRuleRegex = regexp.MustCompile(`^(<[^>]+>|[A-Za-z]{3,})[+-]?\d`)
+
 // Name tries to find the local timezone configuration. It returns the timezone name
 // if found. If not, an error is returned.
 func Name() (string, error) {
+	name, _, err := NameWithSource()
+	return name, err
+}
+
+// NameWithSource behaves like Name, but additionally reports which source the
+// timezone name was resolved from.
+func NameWithSource() (string, TZSource, error) {
 	// first try the ENV setting
-	if tzenv := parseEnv(); tzenv != "" {
-		return tzenv, nil
+	if tzenv, set := parseEnv(); set && tzenv != "" {
+		return tzenv, SourceEnv, nil
 	}
 
 	// now look for distribution specific configuration files
@@ -30,29 +50,141 @@ func Name() (string, error) {
 
 	timezones = append(timezones, parseFromConfigFile([]string{
 		"/etc/timezone",
-		"/var/db/zoneinfo"})...)
+		"/var/db/zoneinfo", // FreeBSD
+	})...)
 
 	timezones = append(timezones, parseFromClock([]string{
 		"/etc/sysconfig/clock",
 		"/etc/conf.d/clock"})...)
 
-	parsed := parseSymlink("/etc/localtime")
-	if parsed != "" {
-		timezones = append(timezones, parsed)
+	timezones = append(timezones, parseFromEnvFile([]string{
+		"/etc/default/init", // Solaris
+		"/etc/environment",  // AIX
+	})...)
+
+	symlinked := parseSymlink("/etc/localtime")
+	if symlinked != "" {
+		timezones = append(timezones, symlinked)
 	}
 
-	return resolveTimezones(timezones, "/usr/share/zoneinfo")
+	zoneinfo := zoneinfoDir(defaultZoneinfoDir)
+
+	name, err := resolveTimezones(timezones, zoneinfo)
+	if err != nil {
+		return "", "", err
+	}
+
+	if name != "" {
+		if symlinked != "" && name == symlinked {
+			return name, SourceSymlink, nil
+		}
+
+		return name, SourceConfigFile, nil
+	}
+
+	// NetBSD and OpenBSD configure /etc/localtime as a hardlink into
+	// zoneinfo rather than a symlink; find the zone file sharing its inode.
+	if hardlinked := resolveByHardlink("/etc/localtime", zoneinfo); hardlinked != "" {
+		return hardlinked, SourceHardlink, nil
+	}
+
+	// last resort: /etc/localtime may be a regular file rather than a
+	// symlink (common on Debian, Alpine and other container base images).
+	// Match its contents against zoneinfo by fingerprint.
+	if localtime, err := os.ReadFile("/etc/localtime"); err == nil {
+		if fingerprint, err := resolveByFingerprint(localtime, zoneinfo); err == nil && fingerprint != "" {
+			return fingerprint, SourceFingerprint, nil
+		}
+	}
+
+	return "", "", nil
 }
 
-// parseEnv parses timezone from TZ env var.
-func parseEnv() string {
-	tzenv := os.Getenv("TZ")
-	if tzenv == "" {
+// parseFromEnvFile parses unquoted "TZ=value" lines out of POSIX style
+// environment files, used by Solaris (/etc/default/init) and AIX
+// (/etc/environment).
+func parseFromEnvFile(paths []string) []string {
+	timezones := []string{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		match := envTZRegex.FindSubmatch(data)
+		if match == nil {
+			continue
+		}
+
+		if tz := strings.Trim(string(match[1]), `"`); tz != "" {
+			timezones = append(timezones, tz)
+		}
+	}
+
+	return timezones
+}
+
+// resolveByHardlink looks for a file under zoneinfoDir that shares path's
+// device and inode, which is how NetBSD and OpenBSD configure
+// /etc/localtime: a hardlink rather than a symlink.
+func resolveByHardlink(path, zoneinfoDir string) string {
+	target, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+
+	targetStat, ok := target.Sys().(*syscall.Stat_t)
+	if !ok {
 		return ""
 	}
 
+	var found string
+
+	_ = filepath.WalkDir(zoneinfoDir, func(candidate string, d fs.DirEntry, err error) error {
+		if found != "" || err != nil || d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || stat.Dev != targetStat.Dev || stat.Ino != targetStat.Ino {
+			return nil
+		}
+
+		if rel, err := filepath.Rel(zoneinfoDir, candidate); err == nil {
+			found = filepath.ToSlash(rel)
+		}
+
+		return nil
+	})
+
+	return found
+}
+
+// parseEnv parses the timezone from the TZ environment variable. The second
+// return value reports whether TZ was set at all, so an unset TZ can be told
+// apart from one explicitly set to an empty string.
+func parseEnv() (string, bool) {
+	tzenv, ok := os.LookupEnv("TZ")
+	if !ok {
+		return "", false
+	}
+
+	// a leading colon forces interpretation as a file path or zone name, per POSIX.
+	tzenv = strings.TrimPrefix(tzenv, ":")
+
+	if tzenv == "" {
+		// an explicitly empty TZ always means UTC.
+		return "UTC", true
+	}
+
 	if _, ok := timezones[tzenv]; ok {
-		return tzenv
+		return tzenv, true
 	}
 
 	if filepath.IsAbs(tzenv) && fileExists(tzenv) {
@@ -62,16 +194,30 @@ func parseEnv() string {
 		// is it a zone info zone?
 		joined := strings.Join(parts[len(parts)-2:], "/")
 		if _, ok := timezones[joined]; ok {
-			return joined
+			return joined, true
 		}
 
 		// maybe it's a short one, like UTC?
 		if _, ok := timezones[parts[len(parts)-1]]; ok {
-			return parts[len(parts)-1]
+			return parts[len(parts)-1], true
+		}
+
+		// it's some other absolute path (e.g. "/etc/localtime" itself, or a
+		// bind-mounted zone file) that doesn't look like a zoneinfo tree
+		// entry by name alone; follow it by realpath or fingerprint instead.
+		if name, ok := resolveAbsoluteTZPath(tzenv); ok {
+			return name, true
 		}
 	}
 
-	return ""
+	if posixRuleRegex.MatchString(tzenv) {
+		// not an Olson name, but a valid inline POSIX rule. Synthesize an
+		// identifier so callers can detect it and fall back to
+		// time.LoadLocation("") plus the rule, or similar handling.
+		return "POSIX/" + tzenv, true
+	}
+
+	return "", true
 }
 
 // parse parses timezone from configuration files.
@@ -177,7 +323,10 @@ func parseSymlink(path string) string {
 	return ""
 }
 
-// resolveTimezones resolves conflicted timezones. Otherwise returns an error.
+// resolveTimezones resolves conflicted timezones. When zoneinfo doesn't
+// exist on disk (e.g. a minimal container without /usr/share/zoneinfo),
+// candidates are instead validated against the tzdata embedded in this
+// module. Otherwise returns an error.
 func resolveTimezones(timezones []string, zoneinfo string) (string, error) {
 	if len(timezones) == 0 {
 		return "", nil
@@ -196,6 +345,14 @@ func resolveTimezones(timezones []string, zoneinfo string) (string, error) {
 		// look them up in '/usr/share/zoneinfo', and find what they really point to
 		path, err := realpath.Realpath(filepath.Join(zoneinfo, tzname))
 		if err != nil {
+			// the zoneinfo directory may not exist at all, e.g. on a
+			// scratch container; fall back to validating the candidate
+			// against the tzdata embedded in this module instead of
+			// silently dropping it.
+			if _, embErr := loadEmbeddedTZData(tzname); embErr == nil {
+				filtered = appendIfMissing(filtered, tzname)
+			}
+
 			continue
 		}
 
@@ -1,4 +1,4 @@
-//go:build darwin || linux
+//go:build unix && !android && !ios
 
 package timezone
 
@@ -165,17 +165,86 @@ func TestResolveTimezones_Conflicting(t *testing.T) {
 		fmt.Sprintf("error %q differs from the string set", err))
 }
 
+func TestResolveTimezones_EmbeddedFallback(t *testing.T) {
+	nonexistent := filepath.Join(t.TempDir(), "does-not-exist")
+
+	tz, err := resolveTimezones([]string{"America/Sao_Paulo", "America/Sao_Paulo"}, nonexistent)
+	require.NoError(t, err)
+
+	assert.Equal(t, "America/Sao_Paulo", tz)
+}
+
+func TestResolveTimezones_EmbeddedFallback_Conflicting(t *testing.T) {
+	nonexistent := filepath.Join(t.TempDir(), "does-not-exist")
+
+	_, err := resolveTimezones([]string{"America/Sao_Paulo", "Europe/Zurich"}, nonexistent)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "multiple conflicting time zone configurations found:\n")
+}
+
 func TestEnv(t *testing.T) {
 	err := os.Setenv("TZ", "America/Sao_Paulo")
 	require.NoError(t, err)
 
 	defer os.Unsetenv("TZ")
 
-	tz := parseEnv()
+	tz, set := parseEnv()
 
+	assert.True(t, set)
 	assert.Equal(t, "America/Sao_Paulo", tz)
 }
 
+func TestEnvTZUsage(t *testing.T) {
+	tests := map[string]struct {
+		Unset    bool
+		Value    string
+		Expected string
+	}{
+		"unset": {
+			Unset:    true,
+			Expected: "",
+		},
+		"empty": {
+			Value:    "",
+			Expected: "UTC",
+		},
+		"colon prefixed zone name": {
+			Value:    ":America/Sao_Paulo",
+			Expected: "America/Sao_Paulo",
+		},
+		"zone name": {
+			Value:    "America/Sao_Paulo",
+			Expected: "America/Sao_Paulo",
+		},
+		"posix rule": {
+			Value:    "EST5EDT,M3.2.0,M11.1.0",
+			Expected: "POSIX/EST5EDT,M3.2.0,M11.1.0",
+		},
+		"unknown": {
+			Value:    "Not/A_Real_Zone",
+			Expected: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.Unset {
+				require.NoError(t, os.Unsetenv("TZ"))
+			} else {
+				require.NoError(t, os.Setenv("TZ", test.Value))
+			}
+
+			defer os.Unsetenv("TZ")
+
+			tz, set := parseEnv()
+
+			assert.Equal(t, !test.Unset, set)
+			assert.Equal(t, test.Expected, tz)
+		})
+	}
+}
+
 func TestEnv_Filepath(t *testing.T) {
 	tests := map[string]struct {
 		Filepath        string
@@ -216,13 +285,77 @@ func TestEnv_Filepath(t *testing.T) {
 
 			defer os.Unsetenv("TZ")
 
-			tz := parseEnv()
+			tz, set := parseEnv()
 
+			assert.True(t, set)
 			assert.Equal(t, test.Expected, tz)
 		})
 	}
 }
 
+func TestEnv_AbsolutePathSymlink(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	zoneinfo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(zoneinfo, "America"), 0700))
+
+	zoneFile := filepath.Join(zoneinfo, "America", "Sao_Paulo")
+	require.NoError(t, os.WriteFile(zoneFile, []byte("TZif"), 0600))
+
+	SetZoneinfoDir(zoneinfo)
+
+	// a symlink whose own name doesn't look like "<Area>/<Location>",
+	// mirroring the request's example of TZ=":/etc/localtime".
+	localtime := filepath.Join(t.TempDir(), "localtime")
+	require.NoError(t, os.Symlink(zoneFile, localtime))
+
+	require.NoError(t, os.Setenv("TZ", ":"+localtime))
+	defer os.Unsetenv("TZ")
+
+	tz, set := parseEnv()
+
+	assert.True(t, set)
+	assert.Equal(t, "America/Sao_Paulo", tz)
+}
+
+func TestEnv_AbsolutePathFingerprint(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	zoneinfo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(zoneinfo, "America"), 0700))
+
+	data := minimalTZif()
+	require.NoError(t, os.WriteFile(filepath.Join(zoneinfo, "America", "Sao_Paulo"), data, 0600))
+
+	SetZoneinfoDir(zoneinfo)
+
+	// a plain copy of the zone file, named like /etc/localtime is on
+	// Debian/Alpine, rather than a symlink into zoneinfo.
+	localtime := filepath.Join(t.TempDir(), "localtime")
+	require.NoError(t, os.WriteFile(localtime, data, 0600))
+
+	require.NoError(t, os.Setenv("TZ", ":"+localtime))
+	defer os.Unsetenv("TZ")
+
+	tz, set := parseEnv()
+
+	assert.True(t, set)
+	assert.Equal(t, "America/Sao_Paulo", tz)
+}
+
+// minimalTZif is a hand-built v1 TZif payload describing UTC: no
+// transitions and a single zero-offset, non-DST type named "UTC".
+func minimalTZif() []byte {
+	data := make([]byte, 44)
+	copy(data, "TZif")
+	data[39] = 1 // tzh_typecnt = 1
+	data[43] = 4 // tzh_charcnt = 4 ("UTC\x00")
+	data = append(data, 0, 0, 0, 0, 0, 0)
+	data = append(data, []byte("UTC\x00")...)
+
+	return data
+}
+
 func copyFile(t *testing.T, source, destination string) {
 	input, err := os.ReadFile(source)
 	require.NoError(t, err)
@@ -230,3 +363,149 @@ func copyFile(t *testing.T, source, destination string) {
 	err = os.WriteFile(destination, input, 0600)
 	require.NoError(t, err)
 }
+
+func TestParseFromEnvFile(t *testing.T) {
+	tests := map[string]struct {
+		Filepath string
+		Expected string
+	}{
+		"solaris": {
+			Filepath: "testdata/etc/default/init",
+			Expected: "America/Sao_Paulo",
+		},
+		"aix": {
+			Filepath: "testdata/etc/environment",
+			Expected: "America/Sao_Paulo",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tz := parseFromEnvFile([]string{test.Filepath})
+
+			assert.Equal(t, []string{test.Expected}, tz)
+		})
+	}
+}
+
+func TestParseFromEnvFile_MissingFile(t *testing.T) {
+	tz := parseFromEnvFile([]string{"testdata/does-not-exist"})
+
+	assert.Equal(t, []string{}, tz)
+}
+
+func TestParseFromEnvFile_EdgeCases(t *testing.T) {
+	tests := map[string]struct {
+		Contents string
+		Expected []string
+	}{
+		"quoted value": {
+			Contents: `TZ="America/Sao_Paulo"` + "\n",
+			Expected: []string{"America/Sao_Paulo"},
+		},
+		"no TZ line": {
+			Contents: "LANG=en_US.UTF-8\n",
+			Expected: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "envfile")
+			require.NoError(t, os.WriteFile(path, []byte(test.Contents), 0600))
+
+			tz := parseFromEnvFile([]string{path})
+
+			assert.Equal(t, test.Expected, tz)
+		})
+	}
+}
+
+func TestResolveByHardlink(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoneinfoDir := filepath.Join(tmpDir, "zoneinfo", "America")
+	require.NoError(t, os.MkdirAll(zoneinfoDir, 0700))
+
+	zoneFile := filepath.Join(zoneinfoDir, "Sao_Paulo")
+	require.NoError(t, os.WriteFile(zoneFile, []byte("TZif"), 0600))
+
+	localtime := filepath.Join(tmpDir, "localtime")
+	require.NoError(t, os.Link(zoneFile, localtime))
+
+	tz := resolveByHardlink(localtime, filepath.Join(tmpDir, "zoneinfo"))
+
+	assert.Equal(t, "America/Sao_Paulo", tz)
+}
+
+func TestResolveByHardlink_NoMatch(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "zoneinfo", "America"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "zoneinfo", "America", "Sao_Paulo"), []byte("TZif"), 0600))
+
+	localtime := filepath.Join(tmpDir, "localtime")
+	require.NoError(t, os.WriteFile(localtime, []byte("TZif"), 0600))
+
+	tz := resolveByHardlink(localtime, filepath.Join(tmpDir, "zoneinfo"))
+
+	assert.Empty(t, tz)
+}
+
+func TestResolveByFingerprint(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoneinfoDir := filepath.Join(tmpDir, "zoneinfo")
+	require.NoError(t, os.MkdirAll(filepath.Join(zoneinfoDir, "America"), 0700))
+
+	data := minimalTZif()
+	require.NoError(t, os.WriteFile(filepath.Join(zoneinfoDir, "America", "Sao_Paulo"), data, 0600))
+
+	tz, err := resolveByFingerprint(data, zoneinfoDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "America/Sao_Paulo", tz)
+}
+
+func TestResolveByFingerprint_NoMatch(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoneinfoDir := filepath.Join(tmpDir, "zoneinfo")
+	require.NoError(t, os.MkdirAll(filepath.Join(zoneinfoDir, "America"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(zoneinfoDir, "America", "Sao_Paulo"), minimalTZif(), 0600))
+
+	otherZone := make([]byte, len(minimalTZif()))
+	copy(otherZone, minimalTZif())
+	otherZone[len(otherZone)-2] = 'X' // perturb the abbreviation so the hash differs
+
+	tz, err := resolveByFingerprint(otherZone, zoneinfoDir)
+	require.NoError(t, err)
+
+	assert.Empty(t, tz)
+}
+
+func TestPickFingerprintCandidate_Default(t *testing.T) {
+	t.Cleanup(func() { SetFingerprintPriority(nil) })
+
+	candidates := []string{"America/Fort_Wayne", "America/Indiana/Indianapolis"}
+
+	assert.Equal(t, "America/Indiana/Indianapolis", pickFingerprintCandidate(candidates))
+}
+
+func TestPickFingerprintCandidate_Configured(t *testing.T) {
+	t.Cleanup(func() { SetFingerprintPriority(nil) })
+
+	SetFingerprintPriority([]string{"America/Fort_Wayne"})
+
+	candidates := []string{"America/Fort_Wayne", "America/Indiana/Indianapolis"}
+
+	assert.Equal(t, "America/Fort_Wayne", pickFingerprintCandidate(candidates))
+}
+
+func TestPickFingerprintCandidate_NoPriorityMatch(t *testing.T) {
+	t.Cleanup(func() { SetFingerprintPriority(nil) })
+
+	candidates := []string{"Pacific/Pago_Pago", "Pacific/Midway"}
+
+	assert.Equal(t, "Pacific/Midway", pickFingerprintCandidate(candidates))
+}
@@ -6,20 +6,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"golang.org/x/sys/windows/registry"
 )
 
+// defaultZoneinfoDir is empty on Windows: there's no native zoneinfo tree,
+// so CountryCodes/ZonesForCountry/CountryForZone rely on the embedded tzdata
+// unless SetZoneinfoDir or ZONEINFO point somewhere else.
+const defaultZoneinfoDir = ""
+
+// posixRuleRegex matches a POSIX TZ rule (e.g. "EST5EDT,M3.2.0,M11.1.0" or
+// "<-04>4") rather than an Olson zone name or file path.
+var posixRuleRegex = regexp.MustCompile(`^(<[^>]+>|[A-Za-z]{3,})[+-]?\d`)
+
 // Name tries to find the local timezone configuration. Windows is special.
 // It has unique time zone names (in several meanings of the word) available,
 // but unfortunately, they can be translated to the language of the operating system,
 // so we need to do a backwards lookup, by going through all time zones and see which
 // one matches.
 func Name() (string, error) {
+	name, _, err := NameWithSource()
+	return name, err
+}
+
+// NameWithSource behaves like Name, but additionally reports which source the
+// timezone name was resolved from.
+func NameWithSource() (string, TZSource, error) {
 	// first try the ENV setting
-	if tzenv := parseEnv(); tzenv != "" {
-		return tzenv, nil
+	if tzenv, set := parseEnv(); set && tzenv != "" {
+		return tzenv, SourceEnv, nil
 	}
 
 	key, err := registry.OpenKey(
@@ -27,14 +44,14 @@ func Name() (string, error) {
 		`SYSTEM\CurrentControlSet\Control\TimeZoneInformation`,
 		registry.QUERY_VALUE)
 	if err != nil {
-		return "", fmt.Errorf("failed to open registry key")
+		return "", "", fmt.Errorf("failed to open registry key")
 	}
 
 	defer key.Close()
 
 	tzwin, _, err := key.GetStringValue("TimeZoneKeyName")
 	if err != nil {
-		return "", fmt.Errorf("can not find windows timezone configuration")
+		return "", "", fmt.Errorf("can not find windows timezone configuration")
 	}
 
 	// for some reason this returns a string with loads of NULL bytes at
@@ -52,21 +69,31 @@ func Name() (string, error) {
 	}
 
 	if !ok {
-		return "", fmt.Errorf("windows timezone '%s' not found", tzwin)
+		return "", "", fmt.Errorf("windows timezone '%s' not found", tzwin)
 	}
 
-	return tz, nil
+	return tz, SourceRegistry, nil
 }
 
-// parseEnv parses timezone from TZ env var.
-func parseEnv() string {
-	tzenv := os.Getenv("TZ")
+// parseEnv parses the timezone from the TZ environment variable. The second
+// return value reports whether TZ was set at all, so an unset TZ can be told
+// apart from one explicitly set to an empty string.
+func parseEnv() (string, bool) {
+	tzenv, ok := os.LookupEnv("TZ")
+	if !ok {
+		return "", false
+	}
+
+	// a leading colon forces interpretation as a file path or zone name, per POSIX.
+	tzenv = strings.TrimPrefix(tzenv, ":")
+
 	if tzenv == "" {
-		return ""
+		// an explicitly empty TZ always means UTC.
+		return "UTC", true
 	}
 
 	if _, ok := timezones[tzenv]; ok {
-		return tzenv
+		return tzenv, true
 	}
 
 	if filepath.IsAbs(tzenv) && fileExists(tzenv) {
@@ -76,16 +103,30 @@ func parseEnv() string {
 		// is it a zone info zone?
 		joined := strings.Join(parts[len(parts)-2:], "/")
 		if _, ok := timezones[joined]; ok {
-			return joined
+			return joined, true
 		}
 
 		// maybe it's a short one, like UTC?
 		if _, ok := timezones[parts[len(parts)-1]]; ok {
-			return parts[len(parts)-1]
+			return parts[len(parts)-1], true
+		}
+
+		// it's some other absolute path that doesn't look like a zoneinfo
+		// tree entry by name alone; follow it by realpath or fingerprint
+		// instead.
+		if name, ok := resolveAbsoluteTZPath(tzenv); ok {
+			return name, true
 		}
 	}
 
-	return ""
+	if posixRuleRegex.MatchString(tzenv) {
+		// not an Olson name, but a valid inline POSIX rule. Synthesize an
+		// identifier so callers can detect it and fall back to
+		// time.LoadLocation("") plus the rule, or similar handling.
+		return "POSIX/" + tzenv, true
+	}
+
+	return "", true
 }
 
 // fileExists checks if a file or directory exist.
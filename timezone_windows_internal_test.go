@@ -17,11 +17,62 @@ func TestEnv(t *testing.T) {
 
 	defer os.Unsetenv("TZ")
 
-	tz := parseEnv()
+	tz, set := parseEnv()
 
+	assert.True(t, set)
 	assert.Equal(t, "America/Sao_Paulo", tz)
 }
 
+func TestEnvTZUsage(t *testing.T) {
+	tests := map[string]struct {
+		Unset    bool
+		Value    string
+		Expected string
+	}{
+		"unset": {
+			Unset:    true,
+			Expected: "",
+		},
+		"empty": {
+			Value:    "",
+			Expected: "UTC",
+		},
+		"colon prefixed zone name": {
+			Value:    ":America/Sao_Paulo",
+			Expected: "America/Sao_Paulo",
+		},
+		"zone name": {
+			Value:    "America/Sao_Paulo",
+			Expected: "America/Sao_Paulo",
+		},
+		"posix rule": {
+			Value:    "EST5EDT,M3.2.0,M11.1.0",
+			Expected: "POSIX/EST5EDT,M3.2.0,M11.1.0",
+		},
+		"unknown": {
+			Value:    "Not/A_Real_Zone",
+			Expected: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.Unset {
+				require.NoError(t, os.Unsetenv("TZ"))
+			} else {
+				require.NoError(t, os.Setenv("TZ", test.Value))
+			}
+
+			defer os.Unsetenv("TZ")
+
+			tz, set := parseEnv()
+
+			assert.Equal(t, !test.Unset, set)
+			assert.Equal(t, test.Expected, tz)
+		})
+	}
+}
+
 func TestEnv_Filepath(t *testing.T) {
 	tests := map[string]struct {
 		Filepath        string
@@ -62,13 +113,53 @@ func TestEnv_Filepath(t *testing.T) {
 
 			defer os.Unsetenv("TZ")
 
-			tz := parseEnv()
+			tz, set := parseEnv()
 
+			assert.True(t, set)
 			assert.Equal(t, test.Expected, tz)
 		})
 	}
 }
 
+func TestEnv_AbsolutePathFingerprint(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	zoneinfo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(zoneinfo, "America"), 0700))
+
+	data := minimalTZif()
+	require.NoError(t, os.WriteFile(filepath.Join(zoneinfo, "America", "Sao_Paulo"), data, 0600))
+
+	SetZoneinfoDir(zoneinfo)
+
+	// a plain copy of the zone file, named like the request's
+	// TZ=":/etc/localtime" example, rather than a path recognizable as a
+	// zoneinfo tree entry by name alone.
+	localtime := filepath.Join(t.TempDir(), "localtime")
+	require.NoError(t, os.WriteFile(localtime, data, 0600))
+
+	require.NoError(t, os.Setenv("TZ", ":"+localtime))
+	defer os.Unsetenv("TZ")
+
+	tz, set := parseEnv()
+
+	assert.True(t, set)
+	assert.Equal(t, "America/Sao_Paulo", tz)
+}
+
+// minimalTZif is a hand-built v1 TZif payload describing UTC: no
+// transitions and a single zero-offset, non-DST type named "UTC".
+func minimalTZif() []byte {
+	data := make([]byte, 44)
+	copy(data, "TZif")
+	data[39] = 1 // tzh_typecnt = 1
+	data[43] = 4 // tzh_charcnt = 4 ("UTC\x00")
+	data = append(data, 0, 0, 0, 0, 0, 0)
+	data = append(data, []byte("UTC\x00")...)
+
+	return data
+}
+
 func copyFile(t *testing.T, source, destination string) {
 	input, err := os.ReadFile(source)
 	require.NoError(t, err)
@@ -0,0 +1,299 @@
+package timezone
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gandarez/go-olson-timezone/internal/tzif"
+	"github.com/yookoala/realpath"
+)
+
+// zoneinfo.zip is a copy of the IANA tzdata, built the same way the Go
+// standard library builds its embedded copy (see time/tzdata), so that
+// LoadTZData keeps working on systems without a /usr/share/zoneinfo
+// directory, such as scratch containers or Windows without network access.
+//
+//go:embed zoneinfo.zip
+var embeddedZoneinfo embed.FS
+
+var (
+	zoneinfoDirMu    sync.RWMutex
+	zoneinfoOverride string
+)
+
+// SetZoneinfoDir overrides the directory used to look up zoneinfo files,
+// taking precedence over the ZONEINFO environment variable and the
+// platform's default location. Pass an empty string to clear the override
+// and fall back to ZONEINFO/the platform default again.
+func SetZoneinfoDir(path string) {
+	zoneinfoDirMu.Lock()
+	defer zoneinfoDirMu.Unlock()
+
+	zoneinfoOverride = path
+}
+
+// zoneinfoDir resolves the zoneinfo directory to use: an explicit
+// SetZoneinfoDir override, then the ZONEINFO environment variable, then the
+// caller supplied platform default.
+func zoneinfoDir(fallback string) string {
+	zoneinfoDirMu.RLock()
+	override := zoneinfoOverride
+	zoneinfoDirMu.RUnlock()
+
+	if override != "" {
+		return override
+	}
+
+	if env := os.Getenv("ZONEINFO"); env != "" {
+		return env
+	}
+
+	return fallback
+}
+
+// LoadTZData returns the raw TZif bytes for the named zone (e.g.
+// "America/Sao_Paulo"), suitable for passing directly to
+// time.LoadLocationFromTZData. It first looks in the configured zoneinfo
+// directory (see SetZoneinfoDir and the ZONEINFO environment variable), and
+// falls back to the tzdata embedded in this module when that directory is
+// unavailable or doesn't contain the zone.
+func LoadTZData(name string) ([]byte, error) {
+	if dir := zoneinfoDir(""); dir != "" {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			return data, nil
+		}
+	}
+
+	return loadEmbeddedTZData(name)
+}
+
+// loadEmbeddedTZData reads name out of the embedded zoneinfo.zip.
+func loadEmbeddedTZData(name string) ([]byte, error) {
+	zr, err := openEmbeddedZoneinfo()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("zone %q not found in embedded tzdata: %w", name, err)
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// openEmbeddedZoneinfo opens the embedded zoneinfo.zip for reading.
+func openEmbeddedZoneinfo() (*zip.Reader, error) {
+	data, err := embeddedZoneinfo.ReadFile("zoneinfo.zip")
+	if err != nil {
+		return nil, err
+	}
+
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// resolveAbsoluteTZPath follows an absolute TZ path (e.g. "/etc/localtime",
+// as set by "TZ=:/etc/localtime") back to the Olson name it refers to. It
+// tries realpath resolution against the configured zoneinfo directory first,
+// then falls back to matching the file's contents by fingerprint, which
+// covers TZ pointing at a plain copy of a zoneinfo file rather than a
+// symlink into the tree.
+func resolveAbsoluteTZPath(path string) (string, bool) {
+	zoneinfo := zoneinfoDir(defaultZoneinfoDir)
+
+	if real, err := realpath.Realpath(path); err == nil {
+		if name, ok := relativeZoneName(real, zoneinfo); ok {
+			return name, true
+		}
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		if name, err := resolveByFingerprint(data, zoneinfo); err == nil && name != "" {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// relativeZoneName reports the Olson name of path relative to zoneinfoDir,
+// if path lies under zoneinfoDir and names a known zone.
+func relativeZoneName(path, zoneinfoDir string) (string, bool) {
+	rel, err := filepath.Rel(zoneinfoDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+
+	name := filepath.ToSlash(rel)
+	if _, ok := timezones[name]; !ok {
+		return "", false
+	}
+
+	return name, true
+}
+
+// defaultFingerprintPriority lists the Olson names preferred, by default,
+// when several zone files under zoneinfoDir are byte-for-byte identical
+// (e.g. America/Indiana/Indianapolis and America/Fort_Wayne).
+var defaultFingerprintPriority = []string{
+	"America/Indiana/Indianapolis",
+	"America/New_York",
+	"America/Los_Angeles",
+	"America/Chicago",
+	"America/Denver",
+	"UTC",
+}
+
+var (
+	fingerprintPriorityMu sync.RWMutex
+	fingerprintPriority   = defaultFingerprintPriority
+)
+
+// SetFingerprintPriority overrides the tie-break order resolveByFingerprint
+// uses when several zone files under zoneinfoDir fingerprint identically.
+// Pass nil to restore the default priority list.
+func SetFingerprintPriority(names []string) {
+	fingerprintPriorityMu.Lock()
+	defer fingerprintPriorityMu.Unlock()
+
+	if names == nil {
+		names = defaultFingerprintPriority
+	}
+
+	fingerprintPriority = names
+}
+
+// resolveByFingerprint parses localtimeBytes as a TZif file and returns the
+// Olson name of the candidate zone whose payload's SHA-256 matches. It walks
+// zoneinfoDir when that directory exists on disk, and falls back to the
+// tzdata embedded in this module otherwise (e.g. a scratch container without
+// a /usr/share/zoneinfo tree). This recovers the zone name when
+// /etc/localtime is a copy of a zoneinfo file rather than a symlink into it.
+func resolveByFingerprint(localtimeBytes []byte, zoneinfoDir string) (string, error) {
+	if _, err := tzif.Parse(localtimeBytes); err != nil {
+		return "", err
+	}
+
+	want := sha256.Sum256(localtimeBytes)
+
+	if _, err := os.Stat(zoneinfoDir); err != nil {
+		return resolveByFingerprintEmbedded(want)
+	}
+
+	var candidates []string
+
+	err := filepath.WalkDir(zoneinfoDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		if _, err := tzif.Parse(data); err != nil {
+			// not a TZif file (e.g. zone.tab, iso3166.tab); skip it.
+			return nil
+		}
+
+		if sha256.Sum256(data) != want {
+			return nil
+		}
+
+		rel, err := filepath.Rel(zoneinfoDir, path)
+		if err != nil {
+			return nil
+		}
+
+		candidates = append(candidates, filepath.ToSlash(rel))
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return pickFingerprintCandidate(candidates), nil
+}
+
+// resolveByFingerprintEmbedded is resolveByFingerprint's counterpart over
+// the tzdata embedded in this module, used when zoneinfoDir doesn't exist on
+// disk.
+func resolveByFingerprintEmbedded(want [sha256.Size]byte) (string, error) {
+	zr, err := openEmbeddedZoneinfo()
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []string
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			continue
+		}
+
+		data, err := io.ReadAll(rc)
+		rc.Close()
+
+		if err != nil {
+			continue
+		}
+
+		if _, err := tzif.Parse(data); err != nil {
+			// not a TZif file (e.g. zone.tab, iso3166.tab); skip it.
+			continue
+		}
+
+		if sha256.Sum256(data) == want {
+			candidates = append(candidates, f.Name)
+		}
+	}
+
+	return pickFingerprintCandidate(candidates), nil
+}
+
+// pickFingerprintCandidate breaks ties between several zone names that
+// fingerprint identically, preferring fingerprintPriority and then the
+// shortest path.
+func pickFingerprintCandidate(candidates []string) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	fingerprintPriorityMu.RLock()
+	priority := fingerprintPriority
+	fingerprintPriorityMu.RUnlock()
+
+	for _, preferred := range priority {
+		for _, candidate := range candidates {
+			if candidate == preferred {
+				return preferred
+			}
+		}
+	}
+
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if len(candidate) < len(best) {
+			best = candidate
+		}
+	}
+
+	return best
+}
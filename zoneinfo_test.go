@@ -0,0 +1,79 @@
+package timezone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneinfoDir(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	require.NoError(t, os.Unsetenv("ZONEINFO"))
+
+	assert.Equal(t, "/usr/share/zoneinfo", zoneinfoDir("/usr/share/zoneinfo"))
+
+	require.NoError(t, os.Setenv("ZONEINFO", "/custom/zoneinfo"))
+	defer os.Unsetenv("ZONEINFO")
+
+	assert.Equal(t, "/custom/zoneinfo", zoneinfoDir("/usr/share/zoneinfo"))
+
+	SetZoneinfoDir("/override/zoneinfo")
+
+	assert.Equal(t, "/override/zoneinfo", zoneinfoDir("/usr/share/zoneinfo"))
+}
+
+func TestLoadTZData_Embedded(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	SetZoneinfoDir(t.TempDir())
+
+	data, err := LoadTZData("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "TZif", string(data[:4]))
+}
+
+func TestLoadTZData_Directory(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "America"), 0o700))
+
+	want := []byte("TZif2 fake but good enough for this test")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "America/Sao_Paulo"), want, 0o600))
+
+	SetZoneinfoDir(dir)
+
+	data, err := LoadTZData("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	assert.Equal(t, want, data)
+}
+
+func TestResolveByFingerprint_Embedded(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	nonexistent := filepath.Join(t.TempDir(), "does-not-exist")
+
+	data, err := loadEmbeddedTZData("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	name, err := resolveByFingerprint(data, nonexistent)
+	require.NoError(t, err)
+
+	assert.Equal(t, "America/Sao_Paulo", name)
+}
+
+func TestLoadTZData_NotFound(t *testing.T) {
+	t.Cleanup(func() { SetZoneinfoDir("") })
+
+	SetZoneinfoDir(t.TempDir())
+
+	_, err := LoadTZData("Not/A_Real_Zone")
+
+	assert.Error(t, err)
+}